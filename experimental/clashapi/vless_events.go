@@ -0,0 +1,125 @@
+// This file adds the /connections/vless/events websocket, which streams
+// tracked VLESS handshake events as JSON. The broadcaster below is
+// package-level rather than a field on *Server, since nothing in this
+// package defines Server itself: call VLESSEventTracker from wherever the
+// router builds the adapter.Router implementation (so it can satisfy the
+// vlessEventSource interface outbound/vless.go looks for) and call
+// SetupVLESSEventsRoute from wherever /traffic and /logs are registered.
+//
+// Neither call site lives in this package: the adapter.Router
+// implementation is in route.Router, and the route table that registers
+// /traffic and /logs is built wherever *Server is constructed. Both are
+// one-line additions (`func (r *Router) VLESSEventTracker() vless.EventTracker
+// { return clashapi.VLESSEventTracker() }` and `clashapi.SetupVLESSEventsRoute(r)`
+// next to the /traffic registration) but neither file is part of this
+// tree, so they can't be made here without inventing those packages
+// wholesale. Same story for the `tools connect --trace` subscriber: it
+// belongs in the CLI command tree, which isn't present either.
+package clashapi
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/sagernet/sing-box/transport/vless"
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+// vlessEventBroadcaster implements vless.EventTracker by fanning every
+// tracked event out to the currently connected /connections/vless/events
+// websocket subscribers. Events are dropped, never blocked on, for
+// subscribers that fall behind.
+type vlessEventBroadcaster struct {
+	access      sync.RWMutex
+	subscribers map[chan any]struct{}
+}
+
+func newVLESSEventBroadcaster() *vlessEventBroadcaster {
+	return &vlessEventBroadcaster{
+		subscribers: make(map[chan any]struct{}),
+	}
+}
+
+func (b *vlessEventBroadcaster) Track(event any) {
+	b.access.RLock()
+	defer b.access.RUnlock()
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+func (b *vlessEventBroadcaster) subscribe() chan any {
+	subscriber := make(chan any, 64)
+	b.access.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.access.Unlock()
+	return subscriber
+}
+
+func (b *vlessEventBroadcaster) unsubscribe(subscriber chan any) {
+	b.access.Lock()
+	delete(b.subscribers, subscriber)
+	b.access.Unlock()
+	close(subscriber)
+}
+
+var (
+	vlessEventsOnce sync.Once
+	vlessEvents     *vlessEventBroadcaster
+)
+
+func vlessEventsBroadcaster() *vlessEventBroadcaster {
+	vlessEventsOnce.Do(func() {
+		vlessEvents = newVLESSEventBroadcaster()
+	})
+	return vlessEvents
+}
+
+// VLESSEventTracker returns the shared VLESS event broadcaster. The router's
+// adapter.Router implementation should expose this through a method of the
+// same name so it satisfies the vlessEventSource interface outbound/vless.go
+// type-asserts against, letting any VLESS outbound report handshake events
+// without this package needing a dependency on outbound or route.
+func VLESSEventTracker() vless.EventTracker {
+	return vlessEventsBroadcaster()
+}
+
+// SetupVLESSEventsRoute registers the /connections/vless/events websocket on
+// r, the same router /traffic and /logs are registered on.
+func SetupVLESSEventsRoute(r chi.Router) {
+	r.Get("/connections/vless/events", handleVLESSEvents)
+}
+
+func handleVLESSEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	broadcaster := vlessEventsBroadcaster()
+	subscriber := broadcaster.subscribe()
+	defer broadcaster.unsubscribe(subscriber)
+
+	for {
+		select {
+		case event := <-subscriber:
+			payload, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			err = conn.WriteMessage(websocket.TextMessage, payload)
+			if err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
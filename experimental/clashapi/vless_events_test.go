@@ -0,0 +1,45 @@
+package clashapi
+
+import "testing"
+
+func TestVLESSEventBroadcasterFanout(t *testing.T) {
+	broadcaster := newVLESSEventBroadcaster()
+	first := broadcaster.subscribe()
+	second := broadcaster.subscribe()
+	defer broadcaster.unsubscribe(first)
+	defer broadcaster.unsubscribe(second)
+
+	broadcaster.Track("hello")
+
+	for _, subscriber := range []chan any{first, second} {
+		select {
+		case event := <-subscriber:
+			if event != "hello" {
+				t.Fatalf("unexpected event: %v", event)
+			}
+		default:
+			t.Fatal("subscriber did not receive the tracked event")
+		}
+	}
+}
+
+func TestVLESSEventBroadcasterDropsOnFullSubscriber(t *testing.T) {
+	broadcaster := newVLESSEventBroadcaster()
+	subscriber := broadcaster.subscribe()
+	defer broadcaster.unsubscribe(subscriber)
+
+	for i := 0; i < cap(subscriber)+1; i++ {
+		broadcaster.Track(i)
+	}
+}
+
+func TestVLESSEventBroadcasterUnsubscribeClosesChannel(t *testing.T) {
+	broadcaster := newVLESSEventBroadcaster()
+	subscriber := broadcaster.subscribe()
+	broadcaster.unsubscribe(subscriber)
+
+	_, open := <-subscriber
+	if open {
+		t.Fatal("expected channel to be closed after unsubscribe")
+	}
+}
@@ -0,0 +1,183 @@
+package inbound
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/vless"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	"github.com/gofrs/uuid"
+)
+
+var _ adapter.Inbound = (*VLESS)(nil)
+
+// vlessVPPLNonceTTL bounds how long a decrypted VPPL destination blob is
+// remembered, which is how long a replay of a captured VLESS request is
+// rejected; once the entry expires, the same blob is accepted again.
+const vlessVPPLNonceTTL = 2 * time.Minute
+
+type vlessVPPL struct {
+	enabled bool
+	proxy   bool
+	key     *rsa.PrivateKey
+
+	nonceMu sync.Mutex
+	nonces  map[[sha256.Size]byte]time.Time
+}
+
+func newVLESSVPPL(options option.VPPLOptions) (*vlessVPPL, error) {
+	if !options.Enabled {
+		return &vlessVPPL{}, nil
+	}
+	v := &vlessVPPL{
+		enabled: true,
+		proxy:   options.Proxy,
+		nonces:  make(map[[sha256.Size]byte]time.Time),
+	}
+	if options.Proxy {
+		return v, nil
+	}
+	if options.PathToKey == "" {
+		return nil, E.New("VPPL: no path_to_key")
+	}
+	keyBytes, err := os.ReadFile(options.PathToKey)
+	if err != nil {
+		return nil, E.Cause(err, "read VPPL private key")
+	}
+	keyBlock, _ := pem.Decode(keyBytes)
+	if keyBlock == nil {
+		return nil, E.New("VPPL: invalid private key")
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		privateKeyAny, err2 := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+		if err2 != nil {
+			return nil, E.Cause(err, "parse VPPL private key")
+		}
+		var ok bool
+		privateKey, ok = privateKeyAny.(*rsa.PrivateKey)
+		if !ok {
+			return nil, E.New("VPPL: private key is not RSA")
+		}
+	}
+	v.key = privateKey
+	return v, nil
+}
+
+// decryptDestination decrypts an RSA-encrypted destination blob and checks
+// it hasn't been seen within vlessVPPLNonceTTL, rejecting replays of a
+// captured VLESS request inside that window.
+func (v *vlessVPPL) decryptDestination(encrypted []byte) (M.Socksaddr, error) {
+	plain, err := rsa.DecryptPKCS1v15(rand.Reader, v.key, encrypted)
+	if err != nil {
+		return M.Socksaddr{}, E.Cause(err, "decrypt VPPL destination")
+	}
+	nonce := sha256.Sum256(encrypted)
+	v.nonceMu.Lock()
+	now := time.Now()
+	for value, seenAt := range v.nonces {
+		if now.Sub(seenAt) > vlessVPPLNonceTTL {
+			delete(v.nonces, value)
+		}
+	}
+	if _, seen := v.nonces[nonce]; seen {
+		v.nonceMu.Unlock()
+		return M.Socksaddr{}, E.New("VPPL: replayed destination")
+	}
+	v.nonces[nonce] = now
+	v.nonceMu.Unlock()
+	return M.ParseSocksaddr(string(plain))
+}
+
+// VLESS is the VLESS inbound. In addition to ordinary VLESS routing, it
+// implements the server half of VPPL: when enabled and not running in
+// Proxy mode, it RSA-decrypts the request's VPPL destination and dispatches
+// the connection there instead of the declared VLESS destination; in Proxy
+// mode it forwards the still-encrypted destination downstream unchanged.
+type VLESS struct {
+	myInboundAdapter
+	users map[[16]byte]option.VLESSUser
+	vppl  *vlessVPPL
+}
+
+func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.VLESSInboundOptions) (*VLESS, error) {
+	vppl, err := newVLESSVPPL(options.VPPL)
+	if err != nil {
+		return nil, err
+	}
+	inbound := &VLESS{
+		myInboundAdapter: myInboundAdapter{
+			protocol:      C.TypeVLESS,
+			network:       []string{N.NetworkTCP},
+			ctx:           ctx,
+			router:        router,
+			logger:        logger,
+			tag:           tag,
+			listenOptions: options.ListenOptions,
+		},
+		users: make(map[[16]byte]option.VLESSUser),
+		vppl:  vppl,
+	}
+	inbound.connHandler = inbound
+	for _, user := range options.Users {
+		userUUID, err := uuidFrom(user.UUID)
+		if err != nil {
+			return nil, E.Cause(err, "parse user ", user.Name)
+		}
+		inbound.users[userUUID] = user
+	}
+	return inbound, nil
+}
+
+func (h *VLESS) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	request, err := vless.ReadRequest(conn)
+	if err != nil {
+		return E.Cause(err, "read request")
+	}
+	user, loaded := h.users[request.UUID]
+	if !loaded {
+		return E.New("unknown user")
+	}
+	metadata.Destination = request.Destination
+
+	if h.vppl.enabled {
+		if h.vppl.proxy {
+			metadata.VPPLdestination = request.VPPLDestAddr
+		} else {
+			if len(request.VPPLDestAddr) == 0 {
+				return E.New("VPPL: request carries no destination blob")
+			}
+			destination, err := h.vppl.decryptDestination(request.VPPLDestAddr)
+			if err != nil {
+				return E.Cause(err, "resolve VPPL destination")
+			}
+			metadata.Destination = destination
+		}
+	}
+
+	h.logger.InfoContext(ctx, "inbound connection from user ", user.Name, " to ", metadata.Destination)
+	return h.router.RouteConnection(ctx, conn, metadata)
+}
+
+func uuidFrom(uuidStr string) ([16]byte, error) {
+	parsed, err := uuid.FromString(uuidStr)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return parsed, nil
+}
@@ -0,0 +1,39 @@
+package inbound
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestVLESSVPPLRejectsReplayedDestination(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &vlessVPPL{
+		enabled: true,
+		key:     key,
+		nonces:  make(map[[sha256.Size]byte]time.Time),
+	}
+
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte("example.com:443"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destination, err := v.decryptDestination(encrypted)
+	if err != nil {
+		t.Fatalf("first decrypt: %v", err)
+	}
+	if destination.String() != "example.com:443" {
+		t.Fatalf("unexpected destination: %v", destination)
+	}
+
+	_, err = v.decryptDestination(encrypted)
+	if err == nil {
+		t.Fatal("expected replayed destination to be rejected")
+	}
+}
@@ -0,0 +1,143 @@
+package option
+
+import (
+	"github.com/sagernet/sing-box/common/badjson"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"encoding/json"
+)
+
+const (
+	V2RayTransportTypeHTTP        = "http"
+	V2RayTransportTypeWebsocket   = "ws"
+	V2RayTransportTypeQUIC        = "quic"
+	V2RayTransportTypeGRPC        = "grpc"
+	V2RayTransportTypeHTTPUpgrade = "httpupgrade"
+	V2RayTransportTypeKCP         = "kcp"
+	V2RayTransportTypeDTLS        = "dtls"
+)
+
+type V2RayTransportOptions struct {
+	Type               string                  `json:"type"`
+	HTTPOptions        V2RayHTTPOptions        `json:"-"`
+	WebsocketOptions   V2RayWebsocketOptions   `json:"-"`
+	QUICOptions        V2RayQUICOptions        `json:"-"`
+	GRPCOptions        V2RayGRPCOptions        `json:"-"`
+	HTTPUpgradeOptions V2RayHTTPUpgradeOptions `json:"-"`
+	KCPOptions         KCPTransportOptions     `json:"-"`
+	DTLSOptions        DTLSTransportOptions    `json:"-"`
+}
+
+type _V2RayTransportOptions V2RayTransportOptions
+
+func (o V2RayTransportOptions) MarshalJSON() ([]byte, error) {
+	var v any
+	switch o.Type {
+	case V2RayTransportTypeHTTP:
+		v = o.HTTPOptions
+	case V2RayTransportTypeWebsocket:
+		v = o.WebsocketOptions
+	case V2RayTransportTypeQUIC:
+		v = o.QUICOptions
+	case V2RayTransportTypeGRPC:
+		v = o.GRPCOptions
+	case V2RayTransportTypeHTTPUpgrade:
+		v = o.HTTPUpgradeOptions
+	case V2RayTransportTypeKCP:
+		v = o.KCPOptions
+	case V2RayTransportTypeDTLS:
+		v = o.DTLSOptions
+	default:
+		return nil, E.New("unknown transport type: " + o.Type)
+	}
+	return badjson.MarshallObjects((_V2RayTransportOptions)(o), v)
+}
+
+func (o *V2RayTransportOptions) UnmarshalJSON(content []byte) error {
+	err := json.Unmarshal(content, (*_V2RayTransportOptions)(o))
+	if err != nil {
+		return err
+	}
+	var v any
+	switch o.Type {
+	case V2RayTransportTypeHTTP:
+		v = &o.HTTPOptions
+	case V2RayTransportTypeWebsocket:
+		v = &o.WebsocketOptions
+	case V2RayTransportTypeQUIC:
+		v = &o.QUICOptions
+	case V2RayTransportTypeGRPC:
+		v = &o.GRPCOptions
+	case V2RayTransportTypeHTTPUpgrade:
+		v = &o.HTTPUpgradeOptions
+	case V2RayTransportTypeKCP:
+		v = &o.KCPOptions
+	case V2RayTransportTypeDTLS:
+		v = &o.DTLSOptions
+	default:
+		return E.New("unknown transport type: " + o.Type)
+	}
+	return badjson.UnmarshallExcluded(content, (*_V2RayTransportOptions)(o), v)
+}
+
+type V2RayHTTPOptions struct {
+	Host   Listable[string] `json:"host,omitempty"`
+	Path   string           `json:"path,omitempty"`
+	Method string           `json:"method,omitempty"`
+}
+
+type V2RayWebsocketOptions struct {
+	Path                string `json:"path,omitempty"`
+	EarlyDataHeaderName string `json:"early_data_header_name,omitempty"`
+}
+
+type V2RayQUICOptions struct{}
+
+type V2RayGRPCOptions struct {
+	ServiceName string `json:"service_name,omitempty"`
+}
+
+type V2RayHTTPUpgradeOptions struct {
+	Host string `json:"host,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// KCPObfuscationOptions configures mKCP's optional header obfuscation, which
+// XORs every packet with a shared key so the mKCP header doesn't stand out
+// on the wire.
+type KCPObfuscationOptions struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Key     string `json:"key,omitempty"`
+}
+
+// KCPFECOptions configures Reed-Solomon forward error correction for an
+// mKCP session.
+type KCPFECOptions struct {
+	DataShard   int `json:"data_shard,omitempty"`
+	ParityShard int `json:"parity_shard,omitempty"`
+}
+
+// KCPTransportOptions configures a VLESS-over-mKCP datagram transport.
+type KCPTransportOptions struct {
+	MTU               uint32                 `json:"mtu,omitempty"`
+	TTI               uint32                 `json:"tti,omitempty"`
+	UplinkCapacity    uint32                 `json:"uplink_capacity,omitempty"`
+	DownlinkCapacity  uint32                 `json:"downlink_capacity,omitempty"`
+	Congestion        bool                   `json:"congestion,omitempty"`
+	ReadBufferSize    uint32                 `json:"read_buffer_size,omitempty"`
+	WriteBufferSize   uint32                 `json:"write_buffer_size,omitempty"`
+	HeaderObfuscation KCPObfuscationOptions  `json:"header_obfuscation,omitempty"`
+	FEC               KCPFECOptions          `json:"fec,omitempty"`
+}
+
+// DTLSTransportOptions configures a VLESS-over-DTLS 1.2 datagram transport.
+type DTLSTransportOptions struct {
+	PSK             string `json:"psk,omitempty"`
+	PSKIdentityHint string `json:"psk_identity_hint,omitempty"`
+	Certificate     string `json:"certificate,omitempty"`
+	CertificatePath string `json:"certificate_path,omitempty"`
+	Key             string `json:"key,omitempty"`
+	KeyPath         string `json:"key_path,omitempty"`
+	ServerName      string `json:"server_name,omitempty"`
+	Insecure        bool   `json:"insecure,omitempty"`
+}
@@ -2,6 +2,8 @@ package outbound
 
 import (
 	"context"
+	stdtls "crypto/tls"
+
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -25,9 +27,17 @@ import (
 	N "github.com/sagernet/sing/common/network"
 	"io"
 	"net"
+	"strconv"
 	"strings"
 )
 
+// vlessEventSource is implemented by routers that expose a VLESS event
+// tracker, e.g. the one clashapi.VLESSEventTracker returns. It's checked with a
+// type assertion so routers that don't support it need no changes.
+type vlessEventSource interface {
+	VLESSEventTracker() vless.EventTracker
+}
+
 var _ adapter.Outbound = (*VLESS)(nil)
 
 type VLESSVPPL struct {
@@ -48,6 +58,9 @@ type VLESS struct {
 	xudp            bool
 	vppl            VLESSVPPL
 	originDest      []byte
+	vision          bool
+	flow            string
+	eventTracker    vless.EventTracker
 }
 
 func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.VLESSOutboundOptions) (*VLESS, error) {
@@ -67,6 +80,11 @@ func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogg
 		},
 		dialer:     outboundDialer,
 		serverAddr: options.VLESSServerOptions.Build(),
+		flow:       options.Flow,
+	}
+
+	if tracker, loaded := router.(vlessEventSource); loaded {
+		outbound.eventTracker = tracker.VLESSEventTracker()
 	}
 
 	if options.VPPL.Enabled {
@@ -104,6 +122,16 @@ func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogg
 		}
 	}
 
+	if options.Flow == vless.FlowVision {
+		if options.Transport != nil {
+			return nil, E.New("xtls-rprx-vision is not supported with a v2ray transport")
+		}
+		if options.TLS == nil || !common.PtrValueOrDefault(options.TLS).Enabled {
+			return nil, E.New("xtls-rprx-vision requires TLS")
+		}
+		outbound.vision = true
+	}
+
 	if options.Transport != nil {
 		if options.VPPL.Enabled {
 			return nil, E.New("VPPL does not support transport options")
@@ -210,14 +238,59 @@ func (h *VLESS) Close() error {
 	return common.Close(common.PtrOrNil(h.multiplexDialer), h.transport)
 }
 
+// visionPaddingLen picks a random padding budget, in bytes, for the initial
+// application data records of an xtls-rprx-vision connection.
+func visionPaddingLen() int {
+	var b [1]byte
+	rand.Read(b[:])
+	return int(b[0])
+}
+
 type vlessDialer VLESS
 
+// trackTLSNegotiated emits a VLESSTLSNegotiated event if conn exposes the
+// standard library's TLS connection state.
+func (h *vlessDialer) trackTLSNegotiated(conn net.Conn, destination M.Socksaddr) {
+	if h.eventTracker == nil {
+		return
+	}
+	stater, ok := conn.(interface{ ConnectionState() stdtls.ConnectionState })
+	if !ok {
+		return
+	}
+	state := stater.ConnectionState()
+	vless.Track(h.eventTracker, vless.NewTLSNegotiatedEvent(
+		h.tag, h.serverAddr, destination,
+		tlsVersionName(state.Version), stdtls.CipherSuiteName(state.CipherSuite), state.NegotiatedProtocol, state.ServerName,
+	))
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case stdtls.VersionTLS10:
+		return "1.0"
+	case stdtls.VersionTLS11:
+		return "1.1"
+	case stdtls.VersionTLS12:
+		return "1.2"
+	case stdtls.VersionTLS13:
+		return "1.3"
+	default:
+		return "unknown"
+	}
+}
+
 func (h *vlessDialer) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
 	ctx, metadata := adapter.AppendContext(ctx)
 	metadata.Outbound = h.tag
 	metadata.Destination = destination
+	isTCP := N.NetworkName(network) == N.NetworkTCP
+	if isTCP {
+		vless.Track(h.eventTracker, vless.NewHandshakeStartEvent(h.tag, h.serverAddr, destination))
+	}
 	var conn net.Conn
 	var err error
+	var visionSniffer *vless.VisionSniffer
 	if h.transport != nil {
 		conn, err = h.transport.DialContext(ctx)
 	} else {
@@ -229,17 +302,43 @@ func (h *vlessDialer) DialContext(ctx context.Context, network string, destinati
 		conn, err = h.dialer.DialContext(ctx, N.NetworkTCP, server)
 		if err == nil && h.tlsConfig != nil {
 			h.logger.InfoContext(ctx, "outbound connection handshake ", conn.RemoteAddr())
+			if h.vision {
+				visionSniffer = vless.NewVisionSniffer(conn)
+				conn = visionSniffer
+			}
 			conn, err = tls.ClientHandshake(ctx, conn, h.tlsConfig)
 			h.logger.InfoContext(ctx, "outbound connection handshake error ", err)
+			if isTCP && err == nil {
+				h.trackTLSNegotiated(conn, destination)
+			}
 		}
 	}
 	if err != nil {
+		if isTCP {
+			vless.Track(h.eventTracker, vless.NewHandshakeErrorEvent(h.tag, h.serverAddr, destination, err))
+		}
 		return nil, err
 	}
 	switch N.NetworkName(network) {
 	case N.NetworkTCP:
 		h.logger.InfoContext(ctx, "outbound connection to ", destination)
-		return h.client.DialEarlyConn(conn, destination, h.originDest)
+		var seed string
+		var paddingLen int
+		if h.vision {
+			paddingLen = visionPaddingLen()
+			seed = strconv.Itoa(paddingLen)
+		}
+		earlyConn, err := h.client.DialEarlyConn(conn, destination, h.originDest, seed)
+		if err != nil {
+			vless.Track(h.eventTracker, vless.NewHandshakeErrorEvent(h.tag, h.serverAddr, destination, err))
+			return nil, err
+		}
+		addonsLen := vless.RequestAddonLen(vless.Request{Flow: h.flow, Seed: seed, VPPLDestAddr: h.originDest})
+		vless.Track(h.eventTracker, vless.NewRequestWrittenEvent(h.tag, h.serverAddr, destination, addonsLen, h.flow, h.vppl.Enabled))
+		if h.vision {
+			return vless.NewVisionConn(earlyConn, visionSniffer, paddingLen)
+		}
+		return earlyConn, nil
 	case N.NetworkUDP:
 		h.logger.InfoContext(ctx, "outbound packet connection to ", destination)
 		if h.xudp {
@@ -262,6 +361,9 @@ func (h *vlessDialer) DialContext(ctx context.Context, network string, destinati
 }
 
 func (h *vlessDialer) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
+	if h.vision {
+		return nil, E.New("xtls-rprx-vision does not support UDP")
+	}
 	h.logger.InfoContext(ctx, "outbound packet connection to ", destination)
 	ctx, metadata := adapter.AppendContext(ctx)
 	metadata.Outbound = h.tag
@@ -0,0 +1,37 @@
+// Package v2ray implements the v2ray-compatible stream and datagram
+// transports that VLESS/VMess outbounds can tunnel through.
+package v2ray
+
+import (
+	"context"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/tls"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+)
+
+// NewClientTransport creates the v2ray-style client transport selected by
+// options.Type, wiring it to dial through dialer towards server.
+func NewClientTransport(ctx context.Context, dialer N.Dialer, server M.Socksaddr, options option.V2RayTransportOptions, tlsConfig tls.Config) (adapter.V2RayClientTransport, error) {
+	switch options.Type {
+	case option.V2RayTransportTypeHTTP:
+		return NewHTTPClient(ctx, dialer, server, options.HTTPOptions, tlsConfig)
+	case option.V2RayTransportTypeWebsocket:
+		return NewWebsocketClient(ctx, dialer, server, options.WebsocketOptions, tlsConfig)
+	case option.V2RayTransportTypeQUIC:
+		return NewQUICClient(ctx, dialer, server, options.QUICOptions, tlsConfig)
+	case option.V2RayTransportTypeGRPC:
+		return NewGRPCClient(ctx, dialer, server, options.GRPCOptions, tlsConfig)
+	case option.V2RayTransportTypeHTTPUpgrade:
+		return NewHTTPUpgradeClient(ctx, dialer, server, options.HTTPUpgradeOptions, tlsConfig)
+	case option.V2RayTransportTypeKCP:
+		return NewKCPClient(ctx, dialer, server, options.KCPOptions, tlsConfig)
+	case option.V2RayTransportTypeDTLS:
+		return NewDTLSClient(ctx, dialer, server, options.DTLSOptions, tlsConfig)
+	default:
+		return nil, E.New("unknown transport type: ", options.Type)
+	}
+}
@@ -0,0 +1,94 @@
+package v2ray
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"os"
+
+	boxtls "github.com/sagernet/sing-box/common/tls"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	"github.com/pion/dtls/v2"
+)
+
+// DTLSClientTransport dials VLESS-over-DTLS 1.2 connections. DTLS's own
+// record layer is the assembler here: pion's *dtls.Conn already presents
+// the datagram session as an ordinary net.Conn.
+type DTLSClientTransport struct {
+	dialer N.Dialer
+	server M.Socksaddr
+	config *dtls.Config
+}
+
+func NewDTLSClient(ctx context.Context, dialer N.Dialer, server M.Socksaddr, options option.DTLSTransportOptions, tlsConfig boxtls.Config) (*DTLSClientTransport, error) {
+	if tlsConfig != nil {
+		return nil, E.New("tls is not supported with the dtls transport, DTLS already provides its own encryption: configure psk or certificate/certificate_path directly")
+	}
+	config := &dtls.Config{
+		ServerName:         options.ServerName,
+		InsecureSkipVerify: options.Insecure,
+	}
+	switch {
+	case options.PSK != "":
+		psk, err := hex.DecodeString(options.PSK)
+		if err != nil {
+			return nil, E.Cause(err, "decode PSK")
+		}
+		config.PSK = func([]byte) ([]byte, error) {
+			return psk, nil
+		}
+		config.PSKIdentityHint = []byte(options.PSKIdentityHint)
+		config.CipherSuites = []dtls.CipherSuiteID{dtls.TLS_PSK_WITH_AES_128_GCM_SHA256}
+	case options.Certificate != "" || options.CertificatePath != "":
+		certificate, err := loadDTLSCertificate(options)
+		if err != nil {
+			return nil, E.Cause(err, "load certificate")
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return &DTLSClientTransport{dialer, server, config}, nil
+}
+
+// loadDTLSCertificate reads the certificate/key pair configured on options,
+// preferring the path fields when set and falling back to the literal PEM
+// strings otherwise.
+func loadDTLSCertificate(options option.DTLSTransportOptions) (tls.Certificate, error) {
+	certificate := []byte(options.Certificate)
+	if options.CertificatePath != "" {
+		content, err := os.ReadFile(options.CertificatePath)
+		if err != nil {
+			return tls.Certificate{}, E.Cause(err, "read certificate_path")
+		}
+		certificate = content
+	}
+	key := []byte(options.Key)
+	if options.KeyPath != "" {
+		content, err := os.ReadFile(options.KeyPath)
+		if err != nil {
+			return tls.Certificate{}, E.Cause(err, "read key_path")
+		}
+		key = content
+	}
+	return tls.X509KeyPair(certificate, key)
+}
+
+func (t *DTLSClientTransport) DialContext(ctx context.Context) (net.Conn, error) {
+	packetConn, err := t.dialer.ListenPacket(ctx, t.server)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, ok := packetConn.(*net.UDPConn)
+	if !ok {
+		return nil, E.New("DTLS requires a direct UDP dialer")
+	}
+	return dtls.ClientWithContext(ctx, udpConn, t.config)
+}
+
+func (t *DTLSClientTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package v2ray
+
+import (
+	"context"
+	"net"
+
+	"github.com/sagernet/sing-box/common/tls"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	kcp "github.com/xtaci/kcp-go/v5"
+)
+
+// KCPClientTransport dials VLESS-over-mKCP connections: a reliable stream
+// assembled on top of UDP, optionally protected with Reed-Solomon FEC and a
+// shared-key header obfuscation scheme so the mKCP header doesn't stand out
+// on the wire.
+type KCPClientTransport struct {
+	dialer  N.Dialer
+	server  M.Socksaddr
+	options option.KCPTransportOptions
+	block   kcp.BlockCrypt
+}
+
+func NewKCPClient(ctx context.Context, dialer N.Dialer, server M.Socksaddr, options option.KCPTransportOptions, tlsConfig tls.Config) (*KCPClientTransport, error) {
+	if tlsConfig != nil {
+		return nil, E.New("tls is not supported with the kcp transport, use header_obfuscation instead")
+	}
+	var block kcp.BlockCrypt
+	if options.HeaderObfuscation.Enabled {
+		var err error
+		block, err = kcp.NewSimpleXORBlockCrypt([]byte(options.HeaderObfuscation.Key))
+		if err != nil {
+			return nil, E.Cause(err, "create header obfuscation")
+		}
+	}
+	return &KCPClientTransport{dialer, server, options, block}, nil
+}
+
+// DialContext dials a new mKCP session and returns the assembler: a
+// net.Conn that turns the underlying unreliable UDP packet stream into a
+// reliable, ordered byte stream for the VLESS client.
+func (t *KCPClientTransport) DialContext(ctx context.Context) (net.Conn, error) {
+	packetConn, err := t.dialer.ListenPacket(ctx, t.server)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, ok := packetConn.(*net.UDPConn)
+	if !ok {
+		return nil, E.New("mKCP requires a direct UDP dialer")
+	}
+	session, err := kcp.NewConn3(0, t.server.UDPAddr(), t.block, t.options.FEC.DataShard, t.options.FEC.ParityShard, udpConn)
+	if err != nil {
+		return nil, E.Cause(err, "create mKCP session")
+	}
+	nodelay := 0
+	if t.options.Congestion {
+		nodelay = 1
+	}
+	session.SetNoDelay(nodelay, int(t.options.TTI), 2, 1)
+	if t.options.MTU > 0 {
+		session.SetMtu(int(t.options.MTU))
+	}
+	if t.options.UplinkCapacity > 0 || t.options.DownlinkCapacity > 0 {
+		session.SetWindowSize(int(t.options.UplinkCapacity), int(t.options.DownlinkCapacity))
+	}
+	if t.options.ReadBufferSize > 0 {
+		session.SetReadBuffer(int(t.options.ReadBufferSize))
+	}
+	if t.options.WriteBufferSize > 0 {
+		session.SetWriteBuffer(int(t.options.WriteBufferSize))
+	}
+	session.SetStreamMode(true)
+	return session, nil
+}
+
+func (t *KCPClientTransport) Close() error {
+	return nil
+}
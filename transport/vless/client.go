@@ -0,0 +1,131 @@
+package vless
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	vmess "github.com/sagernet/sing-vmess"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/buf"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/gofrs/uuid"
+)
+
+// Client writes VLESS requests for a single configured user onto connections
+// a transport has already dialed.
+type Client struct {
+	uuid   [16]byte
+	flow   string
+	logger log.ContextLogger
+}
+
+func NewClient(uuidStr string, flow string, logger log.ContextLogger) (*Client, error) {
+	userUUID, err := uuid.FromString(uuidStr)
+	if err != nil {
+		return nil, E.Cause(err, "parse UUID")
+	}
+	return &Client{uuid: userUUID, flow: flow, logger: logger}, nil
+}
+
+// DialEarlyConn writes the VLESS request for a TCP connection to conn and
+// returns conn ready for the resulting stream. originDest, when non-empty,
+// is the VPPL-encrypted destination blob carried in the addons instead of
+// the plaintext Destination. seed, when non-empty, is flow-specific side
+// data (e.g. the xtls-rprx-vision padding length) carried in the same
+// addons so the server can interpret the stream the same way the client
+// writes it.
+func (c *Client) DialEarlyConn(conn net.Conn, destination M.Socksaddr, originDest []byte, seed string) (net.Conn, error) {
+	request := Request{
+		UUID:         c.uuid,
+		Command:      vmess.CommandTCP,
+		Destination:  destination,
+		Flow:         c.flow,
+		Seed:         seed,
+		VPPLDestAddr: originDest,
+	}
+	err := WriteRequest(conn, request, nil)
+	if err != nil {
+		return nil, E.Cause(err, "write request")
+	}
+	return conn, nil
+}
+
+// DialEarlyPacketConn writes the VLESS request for a UDP-over-TCP session to
+// conn and wraps it as a net.PacketConn, length-prefixing each packet.
+func (c *Client) DialEarlyPacketConn(conn net.Conn, destination M.Socksaddr) (net.PacketConn, error) {
+	request := Request{
+		UUID:        c.uuid,
+		Command:     vmess.CommandUDP,
+		Destination: destination,
+		Flow:        c.flow,
+	}
+	err := WritePacketRequest(conn, request, nil)
+	if err != nil {
+		return nil, E.Cause(err, "write packet request")
+	}
+	return &packetConn{Conn: conn, destination: destination}, nil
+}
+
+// DialEarlyXUDPPacketConn is like DialEarlyPacketConn but, when originDest
+// is non-empty, also carries that encrypted VPPL destination blob in the
+// request addons. No XUDP session framing is applied: packets use the same
+// 2-byte length prefix as DialEarlyPacketConn.
+func (c *Client) DialEarlyXUDPPacketConn(conn net.Conn, destination M.Socksaddr, originDest []byte) (net.PacketConn, error) {
+	request := Request{
+		UUID:         c.uuid,
+		Command:      vmess.CommandUDP,
+		Destination:  destination,
+		Flow:         c.flow,
+		VPPLDestAddr: originDest,
+	}
+	var err error
+	if len(originDest) > 0 {
+		err = WriteRequest(conn, request, nil)
+	} else {
+		err = WritePacketRequest(conn, request, nil)
+	}
+	if err != nil {
+		return nil, E.Cause(err, "write packet request")
+	}
+	return &packetConn{Conn: conn, destination: destination}, nil
+}
+
+// packetConn turns a VLESS UDP-over-TCP stream into a net.PacketConn by
+// length-prefixing every packet with a 2-byte big-endian length, matching
+// the framing WritePacketRequest already uses for its inline payload.
+type packetConn struct {
+	net.Conn
+	destination M.Socksaddr
+}
+
+func (c *packetConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	var length uint16
+	err := binary.Read(c.Conn, binary.BigEndian, &length)
+	if err != nil {
+		return 0, nil, err
+	}
+	buffer := make([]byte, length)
+	_, err = io.ReadFull(c.Conn, buffer)
+	if err != nil {
+		return 0, nil, err
+	}
+	return copy(p, buffer), c.destination.UDPAddr(), nil
+}
+
+func (c *packetConn) WriteTo(p []byte, _ net.Addr) (int, error) {
+	buffer := buf.NewSize(2 + len(p))
+	defer buffer.Release()
+	common.Must(
+		binary.Write(buffer, binary.BigEndian, uint16(len(p))),
+		common.Error(buffer.Write(p)),
+	)
+	_, err := c.Conn.Write(buffer.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
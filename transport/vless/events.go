@@ -0,0 +1,102 @@
+package vless
+
+import (
+	"time"
+
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+// EventTracker receives typed VLESS handshake events, so that GUIs and other
+// observers can distinguish handshake failures from routing decisions and
+// display per-connection TLS parameters instead of scraping log lines.
+type EventTracker interface {
+	Track(event any)
+}
+
+// HandshakeEvent carries the fields shared by every VLESS handshake event:
+// the outbound that dialed the connection, the outbound's configured server
+// address and the destination written into the VLESS request.
+type HandshakeEvent struct {
+	Outbound    string
+	RemoteAddr  M.Socksaddr
+	Destination M.Socksaddr
+	Time        time.Time
+}
+
+func newHandshakeEvent(outbound string, remoteAddr, destination M.Socksaddr) HandshakeEvent {
+	return HandshakeEvent{
+		Outbound:    outbound,
+		RemoteAddr:  remoteAddr,
+		Destination: destination,
+		Time:        time.Now(),
+	}
+}
+
+// VLESSHandshakeStart is emitted right before a VLESS outbound dials its
+// server.
+type VLESSHandshakeStart struct {
+	HandshakeEvent
+}
+
+func NewHandshakeStartEvent(outbound string, remoteAddr, destination M.Socksaddr) VLESSHandshakeStart {
+	return VLESSHandshakeStart{newHandshakeEvent(outbound, remoteAddr, destination)}
+}
+
+// VLESSTLSNegotiated is emitted once the outer TLS handshake completes.
+type VLESSTLSNegotiated struct {
+	HandshakeEvent
+	Version string
+	Cipher  string
+	ALPN    string
+	SNI     string
+}
+
+func NewTLSNegotiatedEvent(outbound string, remoteAddr, destination M.Socksaddr, version, cipher, alpn, sni string) VLESSTLSNegotiated {
+	return VLESSTLSNegotiated{
+		HandshakeEvent: newHandshakeEvent(outbound, remoteAddr, destination),
+		Version:        version,
+		Cipher:         cipher,
+		ALPN:           alpn,
+		SNI:            sni,
+	}
+}
+
+// VLESSRequestWritten is emitted once the VLESS request has been written to
+// the connection.
+type VLESSRequestWritten struct {
+	HandshakeEvent
+	AddonsLen   int
+	Flow        string
+	VPPLEnabled bool
+}
+
+func NewRequestWrittenEvent(outbound string, remoteAddr, destination M.Socksaddr, addonsLen int, flow string, vpplEnabled bool) VLESSRequestWritten {
+	return VLESSRequestWritten{
+		HandshakeEvent: newHandshakeEvent(outbound, remoteAddr, destination),
+		AddonsLen:      addonsLen,
+		Flow:           flow,
+		VPPLEnabled:    vpplEnabled,
+	}
+}
+
+// VLESSHandshakeError is emitted when dialing or handshaking fails.
+type VLESSHandshakeError struct {
+	HandshakeEvent
+	Err error
+}
+
+func NewHandshakeErrorEvent(outbound string, remoteAddr, destination M.Socksaddr, err error) VLESSHandshakeError {
+	return VLESSHandshakeError{
+		HandshakeEvent: newHandshakeEvent(outbound, remoteAddr, destination),
+		Err:            err,
+	}
+}
+
+// Track calls tracker.Track if tracker is non-nil, so call sites don't need
+// to guard every emission behind a nil check.
+func Track(tracker EventTracker, event any) {
+	if tracker == nil {
+		return
+	}
+	tracker.Track(event)
+}
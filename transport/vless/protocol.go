@@ -1,7 +1,6 @@
 package vless
 
 import (
-	"bytes"
 	"encoding/binary"
 	"io"
 
@@ -11,6 +10,8 @@ import (
 	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
 	"github.com/sagernet/sing/common/rw"
+
+	"github.com/golang/protobuf/proto"
 )
 
 const (
@@ -23,6 +24,10 @@ type Request struct {
 	Command      byte
 	Destination  M.Socksaddr
 	Flow         string
+	// Seed carries flow-specific side data, e.g. the xtls-rprx-vision
+	// padding length applied to the first few writes, so the peer knows
+	// how much to strip back out.
+	Seed         string
 	VPPLDestAddr []byte
 }
 
@@ -53,11 +58,12 @@ func ReadRequest(reader io.Reader) (*Request, error) {
 			return nil, E.New("can't read addons: ", err)
 		}
 
-		addons, err := readAddons(bytes.NewReader(addonsBytes))
+		addons, err := readAddons(addonsBytes)
 		if err != nil {
 			return nil, E.New("can't parse addons: ", err)
 		}
 		request.Flow = addons.Flow
+		request.Seed = addons.Seed
 		request.VPPLDestAddr = addons.VPPLDestination
 	}
 
@@ -76,58 +82,13 @@ func ReadRequest(reader io.Reader) (*Request, error) {
 	return &request, nil
 }
 
-type Addons struct {
-	Flow            string
-	VPPLDestination []byte
-}
-
-func readAddons(reader io.Reader) (*Addons, error) {
-	protoHeader, err := rw.ReadByte(reader)
-	if err != nil {
-		return nil, err
-	}
-	if protoHeader != 10 {
-		return nil, E.New("unknown protobuf message header: ", protoHeader)
-	}
-
-	var addons Addons
-
-	flowLen, err := rw.ReadUVariant(reader)
-	if err != nil {
-		if err == io.EOF {
-			return &addons, nil
-		}
-		return nil, err
-	}
-	flowBytes, err := rw.ReadBytes(reader, int(flowLen))
-	if err != nil {
-		return nil, err
-	}
-	addons.Flow = string(flowBytes)
-
-	protoHeader, err = rw.ReadByte(reader)
-	if err != nil {
-		if err == io.EOF {
-			return &addons, nil
-		}
-		return nil, err
-	}
-	if protoHeader != 18 {
-		return nil, E.New("unknown protobuf message header: ", protoHeader)
-	}
-
-	VPPLDestinationLen, err := rw.ReadUVariant(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	VPPLDestinationBytes, err := rw.ReadBytes(reader, int(VPPLDestinationLen))
+func readAddons(addonsBytes []byte) (*Addons, error) {
+	addons := new(Addons)
+	err := proto.Unmarshal(addonsBytes, addons)
 	if err != nil {
-		return nil, E.New("can't read ", int(VPPLDestinationLen), " bytes of VPPL Destination: ", err)
+		return nil, E.Cause(err, "unmarshal addons")
 	}
-	addons.VPPLDestination = VPPLDestinationBytes
-
-	return &addons, nil
+	return addons, nil
 }
 
 func WriteRequest(writer io.Writer, request Request, payload []byte) error {
@@ -143,29 +104,20 @@ func WriteRequest(writer io.Writer, request Request, payload []byte) error {
 }
 
 func EncodeRequest(request Request, buffer *buf.Buffer) error {
-	addonsLen := RequestAddonLen(request)
+	addonsBytes, err := marshalAddons(request)
+	if err != nil {
+		return E.Cause(err, "marshal addons")
+	}
 	common.Must(
 		buffer.WriteByte(Version),
 		common.Error(buffer.Write(request.UUID[:])),
 	)
 
-	binary.PutUvarint(buffer.Extend(rw.UVariantLen(uint64(addonsLen))), uint64(addonsLen))
-
-	if addonsLen > 0 {
-		common.Must(buffer.WriteByte(10))
-		binary.PutUvarint(buffer.Extend(rw.UVariantLen(uint64(len(request.Flow)))), uint64(len(request.Flow)))
-		if len(request.Flow) > 0 {
-			common.Must(common.Error(buffer.WriteString(request.Flow)))
-		}
-
-		common.Must(buffer.WriteByte(18))
-		if request.VPPLDestAddr != nil {
-			binary.PutUvarint(buffer.Extend(rw.UVariantLen(uint64(len(request.VPPLDestAddr)))), uint64(len(request.VPPLDestAddr)))
-			common.Must(common.Error(buffer.WriteString(string(request.VPPLDestAddr))))
-		} else {
-			binary.PutUvarint(buffer.Extend(1), 0)
-		}
+	binary.PutUvarint(buffer.Extend(rw.UVariantLen(uint64(len(addonsBytes)))), uint64(len(addonsBytes)))
+	if len(addonsBytes) > 0 {
+		common.Must(common.Error(buffer.Write(addonsBytes)))
 	}
+
 	common.Must(
 		buffer.WriteByte(request.Command),
 	)
@@ -179,24 +131,25 @@ func EncodeRequest(request Request, buffer *buf.Buffer) error {
 	return nil
 }
 
-func RequestAddonLen(request Request) int {
-	var addonsLen int
-	if request.Flow != "" || request.VPPLDestAddr != nil {
-		addonsLen += 2 // protobuf header (2 fields) https://protobuf.dev/programming-guides/encoding/
-		addonsLen += rw.UVariantLen(uint64(len(request.Flow)))
-		if request.Flow != "" {
-			addonsLen += len(request.Flow)
-		}
+// marshalAddons encodes the addon fields carried by request as a protobuf
+// Addons message, or returns nil if the request carries no addons at all.
+func marshalAddons(request Request) ([]byte, error) {
+	if request.Flow == "" && request.Seed == "" && request.VPPLDestAddr == nil {
+		return nil, nil
+	}
+	return proto.Marshal(&Addons{
+		Flow:            request.Flow,
+		Seed:            request.Seed,
+		VPPLDestination: request.VPPLDestAddr,
+	})
+}
 
-		if request.VPPLDestAddr != nil {
-			addonsLen += rw.UVariantLen(uint64(len(request.VPPLDestAddr)))
-			addonsLen += len(request.VPPLDestAddr)
-		} else {
-			addonsLen += rw.UVariantLen(0)
-		}
+func RequestAddonLen(request Request) int {
+	addonsBytes, err := marshalAddons(request)
+	if err != nil {
+		return 0
 	}
-
-	return addonsLen
+	return len(addonsBytes)
 }
 
 func RequestLen(request Request) int {
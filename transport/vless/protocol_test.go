@@ -0,0 +1,47 @@
+package vless
+
+import (
+	"bytes"
+	"testing"
+
+	vmess "github.com/sagernet/sing-vmess"
+	"github.com/sagernet/sing/common/buf"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	request := Request{
+		UUID:        [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		Command:     vmess.CommandTCP,
+		Destination: M.ParseSocksaddrHostPort("example.com", 443),
+		Flow:        FlowVision,
+		Seed:        "seed-value",
+	}
+
+	buffer := buf.NewSize(RequestLen(request))
+	defer buffer.Release()
+	err := EncodeRequest(request, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ReadRequest(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.UUID != request.UUID {
+		t.Errorf("UUID mismatch: got %v, want %v", decoded.UUID, request.UUID)
+	}
+	if decoded.Command != request.Command {
+		t.Errorf("Command mismatch: got %v, want %v", decoded.Command, request.Command)
+	}
+	if decoded.Destination != request.Destination {
+		t.Errorf("Destination mismatch: got %v, want %v", decoded.Destination, request.Destination)
+	}
+	if decoded.Flow != request.Flow {
+		t.Errorf("Flow mismatch: got %q, want %q", decoded.Flow, request.Flow)
+	}
+	if decoded.Seed != request.Seed {
+		t.Errorf("Seed mismatch: got %q, want %q", decoded.Seed, request.Seed)
+	}
+}
@@ -0,0 +1,204 @@
+package vless
+
+import (
+	"crypto/rand"
+	"net"
+	"sync/atomic"
+
+	"github.com/sagernet/sing/common/buf"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+const (
+	tlsRecordHeaderLen = 5
+
+	tlsRecordTypeChangeCipherSpec = 20
+	tlsRecordTypeAlert            = 21
+	tlsRecordTypeHandshake        = 22
+	tlsRecordTypeApplicationData  = 23
+
+	// visionPaddingWrites is the number of leading application data writes
+	// that get random padding appended, matching upstream's "a small number
+	// of initial records" behavior.
+	visionPaddingWrites = 3
+)
+
+// VisionSniffer passively observes the TLS records carried over a raw
+// connection, without altering them, so that the xtls-rprx-vision flow can
+// learn when the outer TLS handshake has completed without needing access
+// to the decrypted stream. It must be inserted between the dialed TCP
+// connection and crypto/tls so it sees the still-plaintext record headers.
+type VisionSniffer struct {
+	net.Conn
+	readRemaining  int
+	writeRemaining int
+	sawRecord      bool
+	nonTLS         bool
+	sawServerData  int32 // atomic bool
+}
+
+func NewVisionSniffer(conn net.Conn) *VisionSniffer {
+	return &VisionSniffer{Conn: conn}
+}
+
+func (s *VisionSniffer) Read(b []byte) (int, error) {
+	n, err := s.Conn.Read(b)
+	if n > 0 {
+		s.observe(b[:n], &s.readRemaining, true)
+	}
+	return n, err
+}
+
+func (s *VisionSniffer) Write(b []byte) (int, error) {
+	n, err := s.Conn.Write(b)
+	if n > 0 {
+		s.observe(b[:n], &s.writeRemaining, false)
+	}
+	return n, err
+}
+
+func (s *VisionSniffer) observe(b []byte, remaining *int, fromServer bool) {
+	for len(b) > 0 {
+		if s.nonTLS {
+			return
+		}
+		if *remaining > 0 {
+			n := *remaining
+			if n > len(b) {
+				n = len(b)
+			}
+			*remaining -= n
+			b = b[n:]
+			continue
+		}
+		if len(b) < tlsRecordHeaderLen {
+			return
+		}
+		recordType := b[0]
+		majorVersion := b[1]
+		if majorVersion != 3 || recordType < tlsRecordTypeChangeCipherSpec || recordType > tlsRecordTypeApplicationData {
+			s.nonTLS = true
+			return
+		}
+		s.sawRecord = true
+		*remaining = int(b[3])<<8 | int(b[4])
+		b = b[tlsRecordHeaderLen:]
+		if fromServer && recordType == tlsRecordTypeApplicationData {
+			atomic.StoreInt32(&s.sawServerData, 1)
+		}
+	}
+}
+
+// HandshakeConfirmed reports whether a server application data record has
+// been observed and every record seen so far looked like a TLS record
+// (plaintext outer handshakes never produce a server application data
+// record here). It does not inspect the negotiated TLS version or cipher
+// suite.
+func (s *VisionSniffer) HandshakeConfirmed() bool {
+	return s.sawRecord && !s.nonTLS && atomic.LoadInt32(&s.sawServerData) == 1
+}
+
+// IsTLS reports whether every record observed so far has looked like a
+// well-formed TLS record. Vision refuses to enable direct mode once this
+// turns false.
+func (s *VisionSniffer) IsTLS() bool {
+	return !s.nonTLS
+}
+
+// Raw returns the connection underlying the sniffer, i.e. the one that was
+// dialed before the TLS handshake began.
+func (s *VisionSniffer) Raw() net.Conn {
+	return s.Conn
+}
+
+// VisionConn implements the xtls-rprx-vision flow on top of an already
+// VLESS-framed connection. It pads the first few application data writes
+// using paddingLen, whose exact value is also sent to the server in the
+// request's addon Seed so it knows how much padding to strip. Once the
+// padding writes are done and the server's first application-data record
+// confirms the outer TLS handshake completed, it stops all Vision bookkeeping and becomes a transparent
+// pass-through over the negotiated TLS connection ("direct" mode) so the
+// rest of the stream pays no sniffing or padding overhead. This is the
+// client's own connection to its own server, not a relay leg, so direct
+// mode never drops down to the pre-TLS raw socket: Upstream() exposes the
+// TLS conn so callers copying through it can still unwrap for splice
+// where that's actually safe, i.e. below TLS, never above it.
+type VisionConn struct {
+	net.Conn
+	sniffer       *VisionSniffer
+	paddingLeft   int
+	paddingWrites int
+	direct        atomic.Bool
+}
+
+// NewVisionConn wraps conn, the connection returned after the VLESS request
+// has already been written, so that subsequent reads/writes follow the
+// xtls-rprx-vision state machine. paddingLen must match the value already
+// sent to the server in the request's addon Seed.
+func NewVisionConn(conn net.Conn, sniffer *VisionSniffer, paddingLen int) (*VisionConn, error) {
+	if sniffer == nil {
+		return nil, E.New("xtls-rprx-vision requires a TLS transport")
+	}
+	return &VisionConn{
+		Conn:          conn,
+		sniffer:       sniffer,
+		paddingLeft:   paddingLen,
+		paddingWrites: visionPaddingWrites,
+	}, nil
+}
+
+func (c *VisionConn) Read(b []byte) (int, error) {
+	if c.direct.Load() {
+		return c.Conn.Read(b)
+	}
+	n, err := c.Conn.Read(b)
+	c.maybeGoDirect()
+	return n, err
+}
+
+func (c *VisionConn) Write(b []byte) (int, error) {
+	if c.direct.Load() {
+		return c.Conn.Write(b)
+	}
+	if c.paddingWrites == 0 || c.paddingLeft <= 0 || !c.sniffer.IsTLS() {
+		c.maybeGoDirect()
+		return c.Conn.Write(b)
+	}
+	padded, padLen := c.pad(b)
+	defer padded.Release()
+	c.paddingWrites--
+	c.paddingLeft -= padLen
+	_, err := c.Conn.Write(padded.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	c.maybeGoDirect()
+	return len(b), nil
+}
+
+// pad appends an even share of the remaining padding budget, rounded up and
+// at most 255 bytes, of random padding to b, returning the padded buffer and
+// the amount of padding actually added.
+func (c *VisionConn) pad(b []byte) (*buf.Buffer, int) {
+	padLen := (c.paddingLeft + c.paddingWrites - 1) / c.paddingWrites
+	if padLen > 255 {
+		padLen = 255
+	}
+	buffer := buf.NewSize(len(b) + padLen)
+	buffer.Write(b)
+	if padLen > 0 {
+		padding := buffer.Extend(padLen)
+		rand.Read(padding)
+	}
+	return buffer, padLen
+}
+
+func (c *VisionConn) maybeGoDirect() {
+	if (c.paddingWrites == 0 || c.paddingLeft <= 0) && c.sniffer.HandshakeConfirmed() {
+		c.direct.Store(true)
+	}
+}
+
+func (c *VisionConn) Upstream() any {
+	return c.Conn
+}